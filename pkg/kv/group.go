@@ -0,0 +1,34 @@
+package kv
+
+// GroupBy groups items by the key returned from keyFn.
+// The returned OrderedKV keeps keys in the order they are first seen,
+// and items within a group keep their original order.
+func GroupBy[T any, K comparable](items []T, keyFn func(T) K) *OrderedKV[K, []T] {
+	rv := NewOrderedKV[K, []T]()
+	for _, item := range items {
+		k := keyFn(item)
+		group, ok := rv.Get(k)
+		if !ok {
+			rv.Put(k, []T{item})
+			continue
+		}
+		rv.kv.Put(k, append(group, item))
+	}
+	return rv
+}
+
+// CountBy counts items by the key returned from keyFn.
+// The returned OrderedKV keeps keys in the order they are first seen.
+func CountBy[T any, K comparable](items []T, keyFn func(T) K) *OrderedKV[K, int] {
+	rv := NewOrderedKV[K, int]()
+	for _, item := range items {
+		k := keyFn(item)
+		count, ok := rv.Get(k)
+		if !ok {
+			rv.Put(k, 1)
+			continue
+		}
+		rv.kv.Put(k, count+1)
+	}
+	return rv
+}
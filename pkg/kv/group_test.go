@@ -0,0 +1,27 @@
+package kv
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestGroupBy(t *testing.T) {
+	words := []string{"banana", "apple", "blueberry", "avocado", "cherry"}
+	first := func(s string) byte { return s[0] }
+
+	groups := GroupBy(words, first)
+	if !slices.Equal(groups.Keys(), []byte{'b', 'a', 'c'}) {
+		t.Fatal(groups.Keys())
+	}
+	if !slices.Equal(groups.Value('b'), []string{"banana", "blueberry"}) {
+		t.Fatal(groups.Value('b'))
+	}
+
+	counts := CountBy(words, first)
+	if !slices.Equal(counts.Keys(), []byte{'b', 'a', 'c'}) {
+		t.Fatal(counts.Keys())
+	}
+	if !slices.Equal(counts.Values(), []int{2, 2, 1}) {
+		t.Fatal(counts.Values())
+	}
+}
@@ -1,6 +1,30 @@
 package kv
 
-import "iter"
+import (
+	"iter"
+	"unsafe"
+)
+
+const (
+	// mapHeaderSize is the approximate size of the runtime map header.
+	mapHeaderSize = 48
+	// mapEntryOverhead is the approximate per-entry bookkeeping cost of a map bucket.
+	// It assumes fully packed buckets, ignoring the load factor slack and the buckets
+	// the runtime keeps after deletes, so real usage is typically higher.
+	mapEntryOverhead = 2
+)
+
+// ComputeAction tells Compute what to do with the value returned by the remapping function.
+type ComputeAction int
+
+const (
+	// ComputeKeep leaves the map unchanged.
+	ComputeKeep ComputeAction = iota
+	// ComputeUpdate stores the returned value, inserting the key if it does not exist.
+	ComputeUpdate
+	// ComputeDelete removes the key from the map.
+	ComputeDelete
+)
 
 // KV is a generic key-value map with comparable keys and any type of values.
 type KV[K comparable, V any] map[K]V
@@ -24,6 +48,28 @@ func (m KV[K, V]) Get(k K) (V, bool) {
 	return v, ok
 }
 
+// GetAll returns a new map holding the entries for the specified keys that exist in the map.
+func (m KV[K, V]) GetAll(keys ...K) KV[K, V] {
+	rv := New[K, V](len(keys))
+	for _, k := range keys {
+		if v, ok := m.Get(k); ok {
+			rv.Put(k, v)
+		}
+	}
+	return rv
+}
+
+// GetMany returns the values for the specified keys and, at the same positions,
+// booleans indicating whether each key exists.
+func (m KV[K, V]) GetMany(keys []K) ([]V, []bool) {
+	values := make([]V, len(keys))
+	oks := make([]bool, len(keys))
+	for i, k := range keys {
+		values[i], oks[i] = m.Get(k)
+	}
+	return values, oks
+}
+
 // Value retrieves the value associated with the specified key.
 // If the key does not exist, the zero value for the value type is returned.
 func (m KV[K, V]) Value(k K) V {
@@ -55,6 +101,14 @@ func (m KV[K, V]) PutAll(p KV[K, V]) KV[K, V] {
 	return m
 }
 
+// Swap inserts or updates a key-value pair in the map like Put,
+// and returns the previous value and whether the key existed.
+func (m KV[K, V]) Swap(k K, v V) (V, bool) {
+	old, ok := m.Get(k)
+	m.Put(k, v)
+	return old, ok
+}
+
 // PutIfAbsent inserts a key-value pair only if the key does not already exist in the map.
 // It returns the updated map.
 func (m KV[K, V]) PutIfAbsent(k K, v V) KV[K, V] {
@@ -64,6 +118,25 @@ func (m KV[K, V]) PutIfAbsent(k K, v V) KV[K, V] {
 	return m
 }
 
+// Compute calls f with the current value of the key and whether it exists,
+// then keeps, updates or deletes the entry according to the returned ComputeAction.
+// f must not modify the map itself. It returns the value held by the key afterward
+// and whether the key exists.
+func (m KV[K, V]) Compute(k K, f func(cur V, existed bool) (V, ComputeAction)) (V, bool) {
+	cur, ok := m.Get(k)
+	v, action := f(cur, ok)
+	switch action {
+	case ComputeUpdate:
+		m.Put(k, v)
+		return v, true
+	case ComputeDelete:
+		delete(m, k)
+		var zero V
+		return zero, false
+	}
+	return cur, ok
+}
+
 // Remove deletes a key-value pair from the map based on the specified key.
 // It returns the removed value.
 func (m KV[K, V]) Remove(k K) V {
@@ -108,6 +181,21 @@ func (m KV[K, V]) Clone() KV[K, V] {
 	return New[K, V](m.Size()).PutAll(m)
 }
 
+// Combine creates and returns a new map holding the entries of both the current map and the provided map.
+// When a key exists in both maps, resolve is called with the key and both values and its result is stored.
+// Neither input map is modified.
+func (m KV[K, V]) Combine(o KV[K, V], resolve func(k K, mv V, ov V) V) KV[K, V] {
+	rv := m.Clone()
+	for k, ov := range o {
+		if mv, ok := rv.Get(k); ok {
+			rv.Put(k, resolve(k, mv, ov))
+		} else {
+			rv.Put(k, ov)
+		}
+	}
+	return rv
+}
+
 // Iterator returns a sequence function that iterates over the key-value pairs
 // in the KV map. The iteration stops if the yield function returns false.
 func (m KV[K, V]) Iterator() iter.Seq2[K, V] {
@@ -127,6 +215,47 @@ func (m KV[K, V]) ForEach(f func(k K, v V)) {
 	}
 }
 
+// EstimateMemory returns a rough estimate in bytes of the memory held by the map.
+// It accounts for the map header and the inline size of every key and value.
+// Memory referenced by keys or values, such as string or slice contents, is only
+// counted when an optional sizeOf function is provided. Its result is added per entry.
+// The estimate ignores unused bucket capacity, see mapEntryOverhead, so treat it as a lower bound.
+func (m KV[K, V]) EstimateMemory(sizeOfs ...func(k K, v V) int64) int64 {
+	var (
+		zeroKey   K
+		zeroValue V
+		size      = int64(mapHeaderSize)
+	)
+	size += int64(m.Size()) * (int64(unsafe.Sizeof(zeroKey)) + int64(unsafe.Sizeof(zeroValue)) + mapEntryOverhead)
+	if len(sizeOfs) > 0 && sizeOfs[0] != nil {
+		for k, v := range m {
+			size += sizeOfs[0](k, v)
+		}
+	}
+	return size
+}
+
+// ForEachUntil iterates over the key-value pairs in the map and applies the provided function
+// until it returns stop or a non-nil error. The error, if any, is returned.
+func (m KV[K, V]) ForEachUntil(f func(k K, v V) (stop bool, err error)) error {
+	for k, v := range m {
+		stop, err := f(k, v)
+		if err != nil {
+			return err
+		}
+		if stop {
+			return nil
+		}
+	}
+	return nil
+}
+
+// GetResult retrieves the value associated with the specified key
+// and returns it wrapped in an Optional, as an alternative to Get for fluent code.
+func (m KV[K, V]) GetResult(k K) Optional[V] {
+	return OptionalOf(m.Get(k))
+}
+
 // GetReply retrieves the value associated with the specified key
 // and returns it wrapped in a Reply struct. The function returns
 // a pointer to the Reply struct.
@@ -0,0 +1,141 @@
+package kv
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestKVCombine(t *testing.T) {
+	a := New[string, int]().
+		Put("a", 1).
+		Put("b", 2)
+	b := New[string, int]().
+		Put("b", 20).
+		Put("c", 3)
+
+	c := a.Combine(b, func(_ string, av int, bv int) int {
+		return av + bv
+	})
+	if c.Size() != 3 || c.Value("b") != 22 || c.Value("c") != 3 {
+		t.Fatal(c)
+	}
+	if a.Value("b") != 2 || a.Size() != 2 {
+		t.Fatal("source map modified")
+	}
+}
+
+func TestReadOnly(t *testing.T) {
+	m := NewOrderedKV[string, int]()
+	m.Put("a", 1).
+		Put("b", 2)
+
+	r := NewOrderedReadOnly(m)
+	keys := r.Keys()
+	keys[0] = "z"
+	if m.Keys()[0] != "a" {
+		t.Fatal("view leaked the underlying keys")
+	}
+
+	m.Put("c", 3)
+	if r.Size() != 3 || r.Value("c") != 3 {
+		t.Fatal("view does not reflect the underlying map")
+	}
+}
+
+func TestKVEstimateMemory(t *testing.T) {
+	m := New[string, string]().
+		Put("a", "hello").
+		Put("b", "world")
+
+	inline := m.EstimateMemory()
+	withContent := m.EstimateMemory(func(k string, v string) int64 {
+		return int64(len(k) + len(v))
+	})
+	if inline <= mapHeaderSize {
+		t.Fatal(inline)
+	}
+	if withContent != inline+12 {
+		t.Fatal(withContent, inline)
+	}
+
+	o := NewOrderedKV[string, string]()
+	o.Put("a", "hello").
+		Put("b", "world")
+	if o.EstimateMemory() <= inline {
+		t.Fatal(o.EstimateMemory(), inline)
+	}
+}
+
+func TestKVSwap(t *testing.T) {
+	m := New[string, int]()
+	old, ok := m.Swap("a", 1)
+	if ok || old != 0 {
+		t.Fatal(old, ok)
+	}
+	old, ok = m.Swap("a", 2)
+	if !ok || old != 1 || m.Value("a") != 2 {
+		t.Fatal(old, ok)
+	}
+}
+
+func TestKVGetAllAndGetMany(t *testing.T) {
+	m := New[string, int]().
+		Put("a", 1).
+		Put("b", 2)
+
+	all := m.GetAll("a", "c")
+	if all.Size() != 1 || all.Value("a") != 1 {
+		t.Fatal(all)
+	}
+
+	values, oks := m.GetMany([]string{"b", "c"})
+	if values[0] != 2 || !oks[0] || values[1] != 0 || oks[1] {
+		t.Fatal(values, oks)
+	}
+}
+
+func TestKVForEachUntil(t *testing.T) {
+	m := NewOrderedKV[string, int]()
+	m.Put("a", 1).
+		Put("b", 2).
+		Put("c", 3)
+
+	var visited int
+	err := m.ForEachUntil(func(k string, v int) (bool, error) {
+		visited++
+		return k == "b", nil
+	})
+	if err != nil || visited != 2 {
+		t.Fatal(err, visited)
+	}
+
+	errStop := errors.New("stop")
+	err = New[string, int]().
+		Put("a", 1).
+		ForEachUntil(func(k string, v int) (bool, error) {
+			return false, errStop
+		})
+	if !errors.Is(err, errStop) {
+		t.Fatal(err)
+	}
+}
+
+func TestKVGetResult(t *testing.T) {
+	m := New[string, int]().
+		Put("a", 1)
+
+	if m.GetResult("a").OrElse(-1) != 1 || m.GetResult("b").OrElse(-1) != -1 {
+		t.Fatal("unexpected OrElse result")
+	}
+
+	var got int
+	m.GetResult("a").IfPresent(func(v int) { got = v })
+	m.GetResult("b").IfPresent(func(v int) { got = -1 })
+	if got != 1 {
+		t.Fatal(got)
+	}
+
+	if v, ok := m.GetResult("b").Get(); ok || v != 0 {
+		t.Fatal(v, ok)
+	}
+}
@@ -0,0 +1,40 @@
+package kv
+
+// Optional holds a value that may or may not be present.
+type Optional[V any] struct {
+	v  V
+	ok bool
+}
+
+// Get returns the value and a boolean indicating whether it is present.
+func (o Optional[V]) Get() (V, bool) {
+	return o.v, o.ok
+}
+
+// IsPresent reports whether the value is present.
+func (o Optional[V]) IsPresent() bool {
+	return o.ok
+}
+
+// OrElse returns the value if it is present, or the provided default value otherwise.
+func (o Optional[V]) OrElse(def V) V {
+	if o.ok {
+		return o.v
+	}
+	return def
+}
+
+// IfPresent calls f with the value if it is present.
+func (o Optional[V]) IfPresent(f func(v V)) {
+	if o.ok {
+		f(o.v)
+	}
+}
+
+// OptionalOf returns an Optional holding v when ok is true, and an empty Optional otherwise.
+func OptionalOf[V any](v V, ok bool) Optional[V] {
+	if !ok {
+		return Optional[V]{}
+	}
+	return Optional[V]{v: v, ok: true}
+}
@@ -7,6 +7,7 @@ import (
 	"reflect"
 	"slices"
 	"strings"
+	"unsafe"
 
 	xstrings "github.com/Tangerg/lynx/pkg/strings"
 )
@@ -126,6 +127,24 @@ func (m *OrderedKV[K, V]) Get(k K) (V, bool) {
 	return m.kv.Get(k)
 }
 
+// GetAll returns a new OrderedKV holding the entries for the specified keys that exist in the map,
+// in the order the keys are given.
+func (m *OrderedKV[K, V]) GetAll(keys ...K) *OrderedKV[K, V] {
+	rv := NewOrderedKV[K, V](len(keys))
+	for _, k := range keys {
+		if v, ok := m.Get(k); ok {
+			rv.Put(k, v)
+		}
+	}
+	return rv
+}
+
+// GetMany returns the values for the specified keys and, at the same positions,
+// booleans indicating whether each key exists.
+func (m *OrderedKV[K, V]) GetMany(keys []K) ([]V, []bool) {
+	return m.kv.GetMany(keys)
+}
+
 // Value retrieves the value associated with the specified key.
 // If the key does not exist, the zero value for the value type is returned.
 func (m *OrderedKV[K, V]) Value(k K) V {
@@ -158,6 +177,14 @@ func (m *OrderedKV[K, V]) PutAll(p *OrderedKV[K, V]) *OrderedKV[K, V] {
 	return m
 }
 
+// Swap inserts or updates a key-value pair in the map like Put,
+// and returns the previous value and whether the key existed.
+func (m *OrderedKV[K, V]) Swap(k K, v V) (V, bool) {
+	old, ok := m.Get(k)
+	m.Put(k, v)
+	return old, ok
+}
+
 // PutIfAbsent inserts a key-value pair only if the key does not already exist in the map.
 // It returns the updated map.
 func (m *OrderedKV[K, V]) PutIfAbsent(k K, v V) *OrderedKV[K, V] {
@@ -167,6 +194,29 @@ func (m *OrderedKV[K, V]) PutIfAbsent(k K, v V) *OrderedKV[K, V] {
 	return m
 }
 
+// Compute calls f with the current value of the key and whether it exists,
+// then keeps, updates or deletes the entry according to the returned ComputeAction.
+// An updated key keeps its position, a new key is appended. f must not modify the map itself.
+// It returns the value held by the key afterward and whether the key exists.
+func (m *OrderedKV[K, V]) Compute(k K, f func(cur V, existed bool) (V, ComputeAction)) (V, bool) {
+	cur, ok := m.Get(k)
+	v, action := f(cur, ok)
+	switch action {
+	case ComputeUpdate:
+		if ok {
+			m.kv.Put(k, v)
+		} else {
+			m.Put(k, v)
+		}
+		return v, true
+	case ComputeDelete:
+		m.Remove(k)
+		var zero V
+		return zero, false
+	}
+	return cur, ok
+}
+
 // Remove deletes a key-value pair from the map based on the specified key.
 // It returns the removed value.
 func (m *OrderedKV[K, V]) Remove(k K) V {
@@ -190,6 +240,28 @@ func (m *OrderedKV[K, V]) removeKeyIfExist(k K) {
 	}
 }
 
+// MoveToFront moves an existing key to the front of the key order without changing its value.
+// It returns false if the key does not exist.
+func (m *OrderedKV[K, V]) MoveToFront(k K) bool {
+	if !m.ContainsKey(k) {
+		return false
+	}
+	m.removeKeyIfExist(k)
+	m.keys = slices.Insert(m.keys, 0, k)
+	return true
+}
+
+// MoveToBack moves an existing key to the back of the key order without changing its value.
+// It returns false if the key does not exist.
+func (m *OrderedKV[K, V]) MoveToBack(k K) bool {
+	if !m.ContainsKey(k) {
+		return false
+	}
+	m.removeKeyIfExist(k)
+	m.keys = append(m.keys, k)
+	return true
+}
+
 // Clear removes all key-value pairs from the map.
 // It returns the updated (empty) map.
 func (m *OrderedKV[K, V]) Clear() *OrderedKV[K, V] {
@@ -218,12 +290,33 @@ func (m *OrderedKV[K, V]) Values() []V {
 }
 
 // Clone creates and returns a shallow copy of the current OrderedKV.
+// The copy is independent of the original, so it can serve as a snapshot
+// to iterate over while the original map is being modified.
 func (m *OrderedKV[K, V]) Clone() *OrderedKV[K, V] {
 	return NewOrderedKV[K, V](m.Size()).PutAll(m)
 }
 
+// Combine creates and returns a new OrderedKV holding the entries of both the current map and the provided map.
+// Keys keep the order of the current map, followed by keys only present in the provided map in their order.
+// When a key exists in both maps, resolve is called with the key and both values and its result is stored
+// in place. Neither input map is modified.
+func (m *OrderedKV[K, V]) Combine(o *OrderedKV[K, V], resolve func(k K, mv V, ov V) V) *OrderedKV[K, V] {
+	rv := m.Clone()
+	for _, key := range o.keys {
+		ov := o.Value(key)
+		if mv, ok := rv.Get(key); ok {
+			rv.kv.Put(key, resolve(key, mv, ov))
+		} else {
+			rv.Put(key, ov)
+		}
+	}
+	return rv
+}
+
 // Iterator returns a sequence function that iterates over the key-value pairs
 // in the OrderedKV map. The iteration stops if the yield function returns false.
+// The map must not be modified during iteration, iterate over a Clone instead
+// when entries need to be put or removed along the way.
 func (m *OrderedKV[K, V]) Iterator() iter.Seq2[K, V] {
 	return func(yield func(key K, value V) bool) {
 		for _, key := range m.keys {
@@ -234,7 +327,29 @@ func (m *OrderedKV[K, V]) Iterator() iter.Seq2[K, V] {
 	}
 }
 
+// ReverseIterator returns a sequence function that iterates over the key-value pairs
+// in the OrderedKV map from the last key to the first, without changing the key order.
+// The iteration stops if the yield function returns false.
+func (m *OrderedKV[K, V]) ReverseIterator() iter.Seq2[K, V] {
+	return func(yield func(key K, value V) bool) {
+		for i := len(m.keys) - 1; i >= 0; i-- {
+			key := m.keys[i]
+			if !yield(key, m.Value(key)) {
+				return
+			}
+		}
+	}
+}
+
+// ReverseKeys returns a slice containing all the keys in the map in reverse order of insertion.
+func (m *OrderedKV[K, V]) ReverseKeys() []K {
+	rv := slices.Clone(m.keys)
+	slices.Reverse(rv)
+	return rv
+}
+
 // ForEach iterates over all key-value pairs in the map and applies the provided function.
+// Like Iterator, f must not modify the map.
 func (m *OrderedKV[K, V]) ForEach(f func(k K, v V)) {
 	for _, key := range m.keys {
 		f(key, m.Value(key))
@@ -253,6 +368,36 @@ func (m *OrderedKV[K, V]) Reverse() *OrderedKV[K, V] {
 	return m
 }
 
+// EstimateMemory returns a rough estimate in bytes of the memory held by the map,
+// including the slice that keeps the key order. See [KV.EstimateMemory].
+func (m *OrderedKV[K, V]) EstimateMemory(sizeOfs ...func(k K, v V) int64) int64 {
+	var zeroKey K
+	size := m.kv.EstimateMemory(sizeOfs...)
+	size += int64(unsafe.Sizeof(m.keys)) + int64(cap(m.keys))*int64(unsafe.Sizeof(zeroKey))
+	return size
+}
+
+// ForEachUntil iterates over the key-value pairs in the map and applies the provided function
+// until it returns stop or a non-nil error. The error, if any, is returned.
+func (m *OrderedKV[K, V]) ForEachUntil(f func(k K, v V) (stop bool, err error)) error {
+	for k, v := range m.Iterator() {
+		stop, err := f(k, v)
+		if err != nil {
+			return err
+		}
+		if stop {
+			return nil
+		}
+	}
+	return nil
+}
+
+// GetResult retrieves the value associated with the specified key
+// and returns it wrapped in an Optional, as an alternative to Get for fluent code.
+func (m *OrderedKV[K, V]) GetResult(k K) Optional[V] {
+	return OptionalOf(m.Get(k))
+}
+
 // GetReply retrieves the value associated with the specified key
 // and returns it wrapped in a Reply struct. The function returns
 // a pointer to the Reply struct.
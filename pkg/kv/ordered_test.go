@@ -3,6 +3,7 @@ package kv
 import (
 	"encoding/json"
 	"iter"
+	"slices"
 	"testing"
 )
 
@@ -180,6 +181,28 @@ func TestNewOrderedKV7(t *testing.T) {
 	})
 }
 
+func TestOrderedKVCombine(t *testing.T) {
+	a := NewOrderedKV[string, int]()
+	a.Put("a", 1).
+		Put("b", 2)
+	b := NewOrderedKV[string, int]()
+	b.Put("c", 3).
+		Put("b", 20)
+
+	c := a.Combine(b, func(_ string, av int, bv int) int {
+		return av + bv
+	})
+	if !slices.Equal(c.Keys(), []string{"a", "b", "c"}) {
+		t.Fatal(c.Keys())
+	}
+	if !slices.Equal(c.Values(), []int{1, 22, 3}) {
+		t.Fatal(c.Values())
+	}
+	if a.Value("b") != 2 || a.Size() != 2 {
+		t.Fatal("source map modified")
+	}
+}
+
 func BenchmarkOrderedKVMarshal(b *testing.B) {
 	m1 := NewOrderedKV[string, any]()
 	m1.Put("name", "Alice")
@@ -218,3 +241,83 @@ func BenchmarkOrderedKVUnmarshal(b *testing.B) {
 	}
 	b.StopTimer()
 }
+
+func TestOrderedKVCloneIterateWhileRemoving(t *testing.T) {
+	k := NewOrderedKV[string, int]()
+	k.Put("a", 1).
+		Put("b", 2).
+		Put("c", 3)
+
+	var visited []string
+	for key := range k.Clone().Iterator() {
+		visited = append(visited, key)
+		k.Remove(key)
+	}
+	if !slices.Equal(visited, []string{"a", "b", "c"}) || !k.IsEmpty() {
+		t.Fatal(visited, k.Keys())
+	}
+}
+
+func TestOrderedKVReverseIterator(t *testing.T) {
+	k := NewOrderedKV[string, int]()
+	k.Put("a", 1).
+		Put("b", 2).
+		Put("c", 3)
+
+	var keys []string
+	for key := range k.ReverseIterator() {
+		keys = append(keys, key)
+	}
+	if !slices.Equal(keys, []string{"c", "b", "a"}) ||
+		!slices.Equal(k.ReverseKeys(), keys) ||
+		!slices.Equal(k.Keys(), []string{"a", "b", "c"}) {
+		t.Fatal(keys, k.ReverseKeys(), k.Keys())
+	}
+}
+
+func TestOrderedKVMove(t *testing.T) {
+	k := NewOrderedKV[string, int]()
+	k.Put("a", 1).
+		Put("b", 2).
+		Put("c", 3)
+
+	if !k.MoveToFront("c") || !slices.Equal(k.Keys(), []string{"c", "a", "b"}) {
+		t.Fatal(k.Keys())
+	}
+	if !k.MoveToBack("c") || !slices.Equal(k.Keys(), []string{"a", "b", "c"}) {
+		t.Fatal(k.Keys())
+	}
+	if k.MoveToFront("z") || k.Size() != 3 || k.Value("c") != 3 {
+		t.Fatal(k.Keys())
+	}
+}
+
+func TestOrderedKVCompute(t *testing.T) {
+	k := NewOrderedKV[string, int]()
+	k.Put("a", 1).
+		Put("b", 2)
+
+	increment := func(cur int, existed bool) (int, ComputeAction) {
+		return cur + 1, ComputeUpdate
+	}
+	if v, ok := k.Compute("a", increment); !ok || v != 2 {
+		t.Fatal(v, ok)
+	}
+	if v, ok := k.Compute("c", increment); !ok || v != 1 {
+		t.Fatal(v, ok)
+	}
+	if !slices.Equal(k.Keys(), []string{"a", "b", "c"}) {
+		t.Fatal(k.Keys())
+	}
+
+	if _, ok := k.Compute("b", func(cur int, existed bool) (int, ComputeAction) {
+		return 0, ComputeDelete
+	}); ok || k.ContainsKey("b") {
+		t.Fatal(k.Keys())
+	}
+	if v, ok := k.Compute("a", func(cur int, existed bool) (int, ComputeAction) {
+		return 100, ComputeKeep
+	}); !ok || v != 2 || k.Value("a") != 2 {
+		t.Fatal(v, ok)
+	}
+}
@@ -0,0 +1,94 @@
+package kv
+
+import (
+	"iter"
+	"slices"
+)
+
+// readable is the set of non-mutating operations shared by KV and OrderedKV.
+type readable[K comparable, V any] interface {
+	Size() int
+	IsEmpty() bool
+	Get(k K) (V, bool)
+	Value(k K) V
+	GetOrDefault(k K, def V) V
+	ContainsKey(k K) bool
+	Keys() []K
+	Values() []V
+	Iterator() iter.Seq2[K, V]
+	ForEach(f func(k K, v V))
+}
+
+var _ readable[any, any] = (KV[any, any])(nil)
+var _ readable[any, any] = (*OrderedKV[any, any])(nil)
+
+// ReadOnly is a read-only view over a KV or OrderedKV.
+// It only exposes read operations, so mutation is rejected at compile time
+// rather than at runtime. Reads and iteration delegate to the underlying map,
+// changes made through the original map are visible through the view.
+type ReadOnly[K comparable, V any] struct {
+	m readable[K, V]
+}
+
+// Size returns the number of key-value pairs in the map.
+func (r *ReadOnly[K, V]) Size() int {
+	return r.m.Size()
+}
+
+// IsEmpty checks if the map contains no key-value pairs.
+func (r *ReadOnly[K, V]) IsEmpty() bool {
+	return r.m.IsEmpty()
+}
+
+// Get returns the value associated with the specified key and a boolean indicating whether the key exists.
+func (r *ReadOnly[K, V]) Get(k K) (V, bool) {
+	return r.m.Get(k)
+}
+
+// Value retrieves the value associated with the specified key.
+// If the key does not exist, the zero value for the value type is returned.
+func (r *ReadOnly[K, V]) Value(k K) V {
+	return r.m.Value(k)
+}
+
+// GetOrDefault retrieves the value associated with the specified key,
+// or returns the provided default value if the key does not exist.
+func (r *ReadOnly[K, V]) GetOrDefault(k K, def V) V {
+	return r.m.GetOrDefault(k, def)
+}
+
+// ContainsKey checks if the map contains the specified key.
+func (r *ReadOnly[K, V]) ContainsKey(k K) bool {
+	return r.m.ContainsKey(k)
+}
+
+// Keys returns a copy of the keys in the map, in the underlying map's order.
+func (r *ReadOnly[K, V]) Keys() []K {
+	return slices.Clone(r.m.Keys())
+}
+
+// Values returns a slice containing all the values in the map.
+func (r *ReadOnly[K, V]) Values() []V {
+	return r.m.Values()
+}
+
+// Iterator returns a sequence function that iterates over the key-value pairs
+// in the underlying map. The iteration stops if the yield function returns false.
+func (r *ReadOnly[K, V]) Iterator() iter.Seq2[K, V] {
+	return r.m.Iterator()
+}
+
+// ForEach iterates over all key-value pairs in the map and applies the provided function.
+func (r *ReadOnly[K, V]) ForEach(f func(k K, v V)) {
+	r.m.ForEach(f)
+}
+
+// NewReadOnly creates and returns a read-only view over the provided map.
+func NewReadOnly[K comparable, V any](m KV[K, V]) *ReadOnly[K, V] {
+	return &ReadOnly[K, V]{m: m}
+}
+
+// NewOrderedReadOnly creates and returns a read-only view over the provided OrderedKV.
+func NewOrderedReadOnly[K comparable, V any](m *OrderedKV[K, V]) *ReadOnly[K, V] {
+	return &ReadOnly[K, V]{m: m}
+}
@@ -135,7 +135,8 @@ func (m *Mime) EqualsParams(other *Mime) bool {
 	}
 	var equal = true
 	m.params.ForEach(func(k, v string) {
-		if v != other.params.Value(k) {
+		otherV, ok := other.params.Get(k)
+		if !ok || v != otherV {
 			equal = false
 		}
 	})
@@ -0,0 +1,26 @@
+package mime
+
+import (
+	"testing"
+)
+
+func TestEquals(t *testing.T) {
+	plain, _ := Parse("text/plain")
+	utf8, _ := Parse("text/plain; charset=utf-8")
+	utf8Upper, _ := Parse("text/plain;charset=UTF-8")
+	emptyParam, _ := Parse("text/plain; format=")
+	otherEmptyParam, _ := Parse("text/plain; delsp=")
+
+	if !plain.EqualsTypeAndSubtype(utf8) {
+		t.Fatal("type and subtype should ignore parameters")
+	}
+	if plain.Equals(utf8) {
+		t.Fatal("strict equality should compare parameters")
+	}
+	if !utf8.Equals(utf8Upper) {
+		t.Fatal("charset should compare case-insensitively")
+	}
+	if emptyParam.Equals(otherEmptyParam) {
+		t.Fatal("different parameter names should not be equal")
+	}
+}
@@ -3,21 +3,108 @@ package sse
 import (
 	"bufio"
 	"bytes"
+	"errors"
+	"fmt"
 	"io"
+	"slices"
 	"strconv"
 	"strings"
 )
 
+var (
+	ErrMessageNoContent        = errors.New("sse: message has no content")
+	ErrMessageInvalidEventName = errors.New("sse: event name must not contain line breaks")
+	ErrMessageInvalidField     = errors.New("sse: invalid extension field")
+)
+
+// reservedFields are the field names defined by the SSE specification,
+// they cannot be used as extension fields.
+var reservedFields = []string{"event", "data", "id", "retry"}
+
+// ValidateEventName reports whether name can be sent as an event field,
+// an event name containing CR or LF would break the stream framing.
+func ValidateEventName(name string) error {
+	if strings.ContainsAny(name, "\r\n") {
+		return ErrMessageInvalidEventName
+	}
+	return nil
+}
+
 type Message struct {
 	Event string `json:"event,omitempty"`
 	Data  []byte `json:"data,omitempty"`
 	ID    string `json:"id,omitempty"`
 	Retry int    `json:"retry,omitempty"`
+	// Fields holds non-standard fields, they are encoded after the standard ones
+	// and populated by the decoder for unrecognized field names unless strict mode is on.
+	Fields map[string]string `json:"fields,omitempty"`
+	// Comment holds comment lines, joined with '\n'. Comments are encoded before
+	// the fields and only populated by the decoder when ReaderConfig.DeliverComments is set.
+	// A non-nil empty Comment stands for a bare ":" line, such as a keep-alive.
+	// CR, LF and CRLF all start a new comment line when encoding.
+	Comment []byte `json:"comment,omitempty"`
 }
 
+// Validate checks the message without encoding it, it returns ErrMessageNoContent
+// when no field is set and ErrMessageInvalidEventName when the event name is malformed.
+func (m *Message) Validate() error {
+	if m.ID == "" &&
+		m.Event == "" &&
+		len(m.Data) == 0 &&
+		m.Comment == nil &&
+		len(m.Fields) == 0 &&
+		m.Retry <= 0 {
+		return ErrMessageNoContent
+	}
+	err := ValidateEventName(m.Event)
+	if err != nil {
+		return err
+	}
+	return m.validateFields()
+}
+
+// validateFields rejects extension fields that would break the stream framing:
+// empty, reserved or ':' containing names, and names or values with CR or LF.
+func (m *Message) validateFields() error {
+	for key, value := range m.Fields {
+		if key == "" ||
+			slices.Contains(reservedFields, key) ||
+			strings.ContainsAny(key, ":\r\n") ||
+			strings.ContainsAny(value, "\r\n") {
+			return fmt.Errorf("%w: %q", ErrMessageInvalidField, key)
+		}
+	}
+	return nil
+}
+
+// DataLines returns the data split into the lines it was received as,
+// the decoder terminates every data line with '\n', so the split is lossless.
+func (m *Message) DataLines() [][]byte {
+	if len(m.Data) == 0 {
+		return nil
+	}
+	return bytes.Split(bytes.TrimSuffix(m.Data, []byte{'\n'}), []byte{'\n'})
+}
+
+// Marshal encodes the message, it returns ErrMessageInvalidField when an extension field is malformed.
+// Data is written as one data line per line returned by DataLines, so a decoded message,
+// whose Data ends with '\n', encodes back to the same event.
 func (m *Message) Marshal() ([]byte, error) {
+	err := m.validateFields()
+	if err != nil {
+		return nil, err
+	}
+
 	buf := bytes.NewBuffer(nil)
 
+	if m.Comment != nil {
+		for _, line := range splitLines(m.Comment) {
+			buf.WriteString(":")
+			buf.Write(line)
+			buf.WriteString("\n")
+		}
+	}
+
 	if m.ID != "" {
 		buf.WriteString("id:")
 		buf.WriteString(m.ID)
@@ -30,9 +117,9 @@ func (m *Message) Marshal() ([]byte, error) {
 		buf.WriteString("\n")
 	}
 
-	if len(m.Data) > 0 {
+	for _, line := range m.DataLines() {
 		buf.WriteString("data:")
-		buf.Write(m.Data)
+		buf.Write(line)
 		buf.WriteString("\n")
 	}
 
@@ -42,20 +129,52 @@ func (m *Message) Marshal() ([]byte, error) {
 		buf.WriteString("\n")
 	}
 
+	keys := make([]string, 0, len(m.Fields))
+	for key := range m.Fields {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	for _, key := range keys {
+		buf.WriteString(key)
+		buf.WriteString(":")
+		buf.WriteString(m.Fields[key])
+		buf.WriteString("\n")
+	}
+
 	buf.WriteString("\n")
 
 	return buf.Bytes(), nil
 }
 
+// splitLines splits b on every line terminator the SSE specification recognises:
+// CRLF, a lone CR and a lone LF.
+func splitLines(b []byte) [][]byte {
+	var lines [][]byte
+	for {
+		i := bytes.IndexAny(b, "\r\n")
+		if i == -1 {
+			return append(lines, b)
+		}
+		lines = append(lines, b[:i])
+		if b[i] == '\r' && i+1 < len(b) && b[i+1] == '\n' {
+			i++
+		}
+		b = b[i+1:]
+	}
+}
+
 type messageDecoder struct {
+	conf           ReaderConfig
+	ndjson         bool
 	currentMessage Message
 	readCloser     io.ReadCloser
 	scanner        *bufio.Scanner
 	error          error
 }
 
-func newMessageDecoder(readCloser io.ReadCloser) *messageDecoder {
+func newMessageDecoder(readCloser io.ReadCloser, conf ReaderConfig) *messageDecoder {
 	return &messageDecoder{
+		conf:       conf,
 		readCloser: readCloser,
 		scanner:    bufio.NewScanner(readCloser),
 	}
@@ -69,22 +188,29 @@ func (e *messageDecoder) Next() bool {
 	if e.error != nil {
 		return false
 	}
+	if e.ndjson {
+		return e.nextNDJSON()
+	}
 
 	var (
-		event = ""
-		data  = bytes.NewBuffer(nil)
-		id    = ""
-		retry = 0
+		event   = ""
+		data    = bytes.NewBuffer(nil)
+		id      = ""
+		retry   = 0
+		fields  map[string]string
+		comment []byte
 	)
 
 	for e.scanner.Scan() {
 		content := e.scanner.Text()
 		if len(content) == 0 {
 			e.currentMessage = Message{
-				Event: event,
-				Data:  data.Bytes(),
-				ID:    id,
-				Retry: retry,
+				Event:   event,
+				Data:    data.Bytes(),
+				ID:      id,
+				Retry:   retry,
+				Fields:  fields,
+				Comment: comment,
 			}
 			return true
 		}
@@ -112,6 +238,24 @@ func (e *messageDecoder) Next() bool {
 			if e.error != nil {
 				break
 			}
+		case "":
+			if !e.conf.DeliverComments {
+				break
+			}
+			if comment == nil {
+				comment = []byte{}
+			} else {
+				comment = append(comment, '\n')
+			}
+			comment = append(comment, value...)
+		default:
+			if e.conf.StrictMode {
+				break
+			}
+			if fields == nil {
+				fields = make(map[string]string)
+			}
+			fields[key] = value
 		}
 
 	}
@@ -119,6 +263,22 @@ func (e *messageDecoder) Next() bool {
 	return false
 }
 
+// nextNDJSON decodes newline-delimited JSON, each non-empty line becomes the data of a message.
+func (e *messageDecoder) nextNDJSON() bool {
+	for e.scanner.Scan() {
+		line := e.scanner.Bytes()
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		e.currentMessage = Message{
+			Data: bytes.Clone(line),
+		}
+		return true
+	}
+	e.error = e.scanner.Err()
+	return false
+}
+
 func (e *messageDecoder) Close() error {
 	return e.readCloser.Close()
 }
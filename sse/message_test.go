@@ -0,0 +1,146 @@
+package sse
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestMessageValidate(t *testing.T) {
+	tests := []struct {
+		message *Message
+		err     error
+	}{
+		{&Message{}, ErrMessageNoContent},
+		{&Message{Data: []byte("hello")}, nil},
+		{&Message{Retry: 1000}, nil},
+		{&Message{Event: "update", Data: []byte("hello")}, nil},
+		{&Message{Event: "up\ndate", Data: []byte("hello")}, ErrMessageInvalidEventName},
+		{&Message{Event: "up\rdate"}, ErrMessageInvalidEventName},
+	}
+	for _, test := range tests {
+		err := test.message.Validate()
+		if !errors.Is(err, test.err) {
+			t.Errorf("Validate(%+v) = %v, want %v", test.message, err, test.err)
+		}
+	}
+}
+
+func TestMessageDecoderFields(t *testing.T) {
+	const stream = "event: update\ntype: delta\ndata: hello\n\n"
+
+	decoder := newMessageDecoder(io.NopCloser(strings.NewReader(stream)), ReaderConfig{})
+	if !decoder.Next() {
+		t.Fatal(decoder.Error())
+	}
+	message := decoder.Current()
+	if message.Event != "update" || message.Fields["type"] != "delta" {
+		t.Fatalf("unexpected message %+v", message)
+	}
+
+	marshal, _ := (&Message{
+		Event:  "update",
+		Data:   []byte("hello"),
+		Fields: map[string]string{"type": "delta"},
+	}).Marshal()
+	if string(marshal) != "event:update\ndata:hello\ntype:delta\n\n" {
+		t.Fatalf("unexpected encoding %q", marshal)
+	}
+
+	decoder = newMessageDecoder(io.NopCloser(strings.NewReader(stream)), ReaderConfig{StrictMode: true})
+	if !decoder.Next() {
+		t.Fatal(decoder.Error())
+	}
+	if decoder.Current().Fields != nil {
+		t.Fatalf("strict mode kept fields %v", decoder.Current().Fields)
+	}
+}
+
+func TestMessageDataLines(t *testing.T) {
+	decoder := newMessageDecoder(io.NopCloser(strings.NewReader("data: a\ndata:\ndata: c\n\n")), ReaderConfig{})
+	if !decoder.Next() {
+		t.Fatal(decoder.Error())
+	}
+	message := decoder.Current()
+	lines := message.DataLines()
+	if len(lines) != 3 || string(lines[0]) != "a" || len(lines[1]) != 0 || string(lines[2]) != "c" {
+		t.Fatalf("unexpected lines %q", lines)
+	}
+	if (&Message{}).DataLines() != nil {
+		t.Fatal("expected no lines for empty data")
+	}
+}
+
+func TestMessageFieldsValidation(t *testing.T) {
+	if err := (&Message{Fields: map[string]string{"type": "ping"}}).Validate(); err != nil {
+		t.Fatalf("fields only message rejected: %v", err)
+	}
+
+	invalid := []map[string]string{
+		{"": "a"},
+		{"id": "1"},
+		{"data": "a"},
+		{"ty:pe": "a"},
+		{"ty\npe": "a"},
+		{"x": "a\nid: evil"},
+		{"x": "a\rb"},
+	}
+	for _, fields := range invalid {
+		message := &Message{Data: []byte("hello"), Fields: fields}
+		if err := message.Validate(); !errors.Is(err, ErrMessageInvalidField) {
+			t.Errorf("Validate(%q) = %v, want %v", fields, err, ErrMessageInvalidField)
+		}
+		if _, err := message.Marshal(); !errors.Is(err, ErrMessageInvalidField) {
+			t.Errorf("Marshal(%q) = %v, want %v", fields, err, ErrMessageInvalidField)
+		}
+	}
+}
+
+func TestMessageRoundTrip(t *testing.T) {
+	const stream = "event: update\ndata: hello\ndata: world\ntype: delta\n\n"
+
+	decode := func(stream string) []Message {
+		var messages []Message
+		decoder := newMessageDecoder(io.NopCloser(strings.NewReader(stream)), ReaderConfig{})
+		for decoder.Next() {
+			messages = append(messages, decoder.Current())
+		}
+		return messages
+	}
+
+	first := decode(stream)
+	if len(first) != 1 {
+		t.Fatalf("unexpected messages %+v", first)
+	}
+	marshal, err := first[0].Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	second := decode(string(marshal))
+	if len(second) != 1 {
+		t.Fatalf("re-encoded %q decoded into %d messages", marshal, len(second))
+	}
+	if second[0].Event != "update" ||
+		string(second[0].Data) != string(first[0].Data) ||
+		second[0].Fields["type"] != "delta" {
+		t.Fatalf("round trip changed the message: %+v", second[0])
+	}
+}
+
+func TestMessageMarshalCommentLineBreaks(t *testing.T) {
+	tests := map[string]string{
+		"a\rid: 9":  ":a\n:id: 9\n\n",
+		"a\r\nb\nc": ":a\n:b\n:c\n\n",
+		"a\n":       ":a\n:\n\n",
+	}
+	for comment, want := range tests {
+		marshal, err := (&Message{Comment: []byte(comment)}).Marshal()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(marshal) != want {
+			t.Errorf("Marshal(%q) = %q, want %q", comment, marshal, want)
+		}
+	}
+}
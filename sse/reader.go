@@ -1,21 +1,91 @@
 package sse
 
 import (
+	"io"
+	"mime"
 	"net/http"
+	"sync"
+	"time"
 )
 
+const eventStreamMediaType = "text/event-stream"
+
+// ReaderConfig controls how a Reader decodes the event stream.
+type ReaderConfig struct {
+	// StrictMode discards fields that are not defined by the SSE specification
+	// instead of collecting them into Message.Fields.
+	StrictMode bool
+	// DeliverComments collects comment lines into Message.Comment instead of
+	// ignoring them, a block holding only comments is delivered as its own message.
+	DeliverComments bool
+	// FallbackNDJSON decodes the body as newline-delimited JSON, one message per line
+	// with the line as Data, when the response Content-Type is not text/event-stream.
+	// It only applies to readers created by NewReader.
+	FallbackNDJSON bool
+	// ReorderWindow buffers up to ReorderWindow messages and delivers them ordered
+	// by numeric ID. The first message is delivered right away and sets the expected ID.
+	// After that a message is delivered as soon as its ID follows the last delivered one,
+	// otherwise when the window is full or ReorderTimeout elapses, and the remaining
+	// messages are drained in order at the end of the stream. A message whose ID is below
+	// the last delivered one arrived too late to be ordered and is delivered as soon as
+	// it is read. Messages without a numeric ID bypass the buffer.
+	ReorderWindow int
+	// ReorderTimeout releases the lowest buffered message once a buffered message has
+	// waited that long, so a gap does not stall a quiet stream. Zero disables it.
+	ReorderTimeout time.Duration
+}
+
 type Reader struct {
 	error        error
 	currentEvent Message
 	response     *http.Response
 	decoder      *messageDecoder
+	reorder      *reorderBuffer
+	messages     chan Message
+	done         chan struct{}
+	closeOnce    sync.Once
+}
+
+func firstReaderConfig(confs []ReaderConfig) ReaderConfig {
+	if len(confs) > 0 {
+		return confs[0]
+	}
+	return ReaderConfig{}
+}
+
+func isEventStream(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	return err == nil && mediaType == eventStreamMediaType
+}
+
+// NewReader creates a Reader decoding the body of resp, with an optional ReaderConfig.
+func NewReader(resp *http.Response, confs ...ReaderConfig) *Reader {
+	conf := firstReaderConfig(confs)
+	decoder := newMessageDecoder(resp.Body, conf)
+	decoder.ndjson = conf.FallbackNDJSON && !isEventStream(resp.Header.Get("Content-Type"))
+	return newReader(resp, decoder, conf)
 }
 
-func NewReader(resp *http.Response) *Reader {
-	return &Reader{
+// NewReaderFromReader creates a Reader decoding an event stream from r, such as a file
+// or a pipe, with an optional ReaderConfig. Close closes r if it implements io.Closer.
+func NewReaderFromReader(r io.Reader, confs ...ReaderConfig) *Reader {
+	readCloser, ok := r.(io.ReadCloser)
+	if !ok {
+		readCloser = io.NopCloser(r)
+	}
+	conf := firstReaderConfig(confs)
+	return newReader(nil, newMessageDecoder(readCloser, conf), conf)
+}
+
+func newReader(resp *http.Response, decoder *messageDecoder, conf ReaderConfig) *Reader {
+	r := &Reader{
 		response: resp,
-		decoder:  newMessageDecoder(resp.Body),
+		decoder:  decoder,
+	}
+	if conf.ReorderWindow > 0 {
+		r.reorder = newReorderBuffer(conf.ReorderWindow, conf.ReorderTimeout)
 	}
+	return r
 }
 
 func (r *Reader) Error() error {
@@ -27,6 +97,10 @@ func (r *Reader) Current() (Message, error) {
 }
 
 func (r *Reader) Next() bool {
+	if r.reorder != nil {
+		return r.nextReordered()
+	}
+
 	err := r.decoder.Error()
 	if err != nil {
 		r.error = err
@@ -41,10 +115,87 @@ func (r *Reader) Next() bool {
 	return true
 }
 
+// startDecoding decodes messages in the background, so that nextReordered
+// can wait for the next message and the reorder timeout at the same time.
+func (r *Reader) startDecoding() {
+	r.messages = make(chan Message)
+	r.done = make(chan struct{})
+	go func() {
+		defer close(r.messages)
+		for r.decoder.Next() {
+			select {
+			case r.messages <- r.decoder.Current():
+			case <-r.done:
+				return
+			}
+		}
+	}()
+}
+
+func (r *Reader) nextReordered() bool {
+	if r.messages == nil {
+		r.startDecoding()
+	}
+
+	for {
+		message, ok := r.reorder.pop(false)
+		if ok {
+			r.currentEvent = message
+			return true
+		}
+
+		var (
+			timer   *time.Timer
+			timeout <-chan time.Time
+		)
+		deadline, ok := r.reorder.deadline()
+		if ok {
+			timer = time.NewTimer(time.Until(deadline))
+			timeout = timer.C
+		}
+
+		select {
+		case message, ok = <-r.messages:
+			if timer != nil {
+				timer.Stop()
+			}
+			if !ok {
+				return r.drainReordered()
+			}
+			if !r.reorder.push(message, time.Now()) {
+				r.currentEvent = message
+				return true
+			}
+		case <-timeout:
+			r.currentEvent, _ = r.reorder.pop(true)
+			return true
+		}
+	}
+}
+
+func (r *Reader) drainReordered() bool {
+	err := r.decoder.Error()
+	if err != nil {
+		r.error = err
+		return false
+	}
+	message, ok := r.reorder.pop(true)
+	if !ok {
+		return false
+	}
+	r.currentEvent = message
+	return true
+}
+
 func (r *Reader) LastID() string {
-	return r.decoder.Current().ID
+	return r.currentEvent.ID
 }
 
 func (r *Reader) Close() error {
+	r.closeOnce.Do(func() {
+		if r.done != nil {
+			close(r.done)
+		}
+	})
 	return r.decoder.Close()
 }
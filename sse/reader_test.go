@@ -0,0 +1,211 @@
+package sse
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewReaderFromReader(t *testing.T) {
+	reader := NewReaderFromReader(strings.NewReader("id: 1\ndata: a\n\nid: 2\ndata: b\n\n"))
+	defer reader.Close()
+
+	var ids []string
+	for reader.Next() {
+		current, err := reader.Current()
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, current.ID)
+	}
+	if reader.Error() != nil {
+		t.Fatal(reader.Error())
+	}
+	if strings.Join(ids, ",") != "1,2" || reader.LastID() != "2" {
+		t.Fatalf("unexpected ids %v, last id %s", ids, reader.LastID())
+	}
+}
+
+func TestReaderDeliverComments(t *testing.T) {
+	const stream = ": keep-alive\n\n: debug\ndata: a\n\n"
+
+	reader := NewReaderFromReader(strings.NewReader(stream), ReaderConfig{DeliverComments: true})
+	var comments []string
+	for reader.Next() {
+		current, _ := reader.Current()
+		comments = append(comments, string(current.Comment))
+	}
+	if strings.Join(comments, ",") != "keep-alive,debug" {
+		t.Fatalf("unexpected comments %q", comments)
+	}
+
+	reader = NewReaderFromReader(strings.NewReader(stream))
+	for reader.Next() {
+		current, _ := reader.Current()
+		if current.Comment != nil {
+			t.Fatalf("comment delivered by default: %q", current.Comment)
+		}
+	}
+}
+
+func TestReaderFallbackNDJSON(t *testing.T) {
+	newResponse := func(contentType string, body string) *http.Response {
+		return &http.Response{
+			Header: http.Header{"Content-Type": []string{contentType}},
+			Body:   io.NopCloser(strings.NewReader(body)),
+		}
+	}
+	conf := ReaderConfig{FallbackNDJSON: true}
+
+	reader := NewReader(newResponse("application/x-ndjson", "{\"a\":1}\n\n{\"b\":2}\n"), conf)
+	var lines []string
+	for reader.Next() {
+		current, _ := reader.Current()
+		lines = append(lines, string(current.Data))
+	}
+	if strings.Join(lines, ",") != `{"a":1},{"b":2}` {
+		t.Fatalf("unexpected lines %q", lines)
+	}
+
+	reader = NewReader(newResponse("text/event-stream; charset=utf-8", "id: 1\ndata: x\n\n"), conf)
+	if !reader.Next() || reader.LastID() != "1" {
+		t.Fatal("event stream was not decoded as SSE")
+	}
+}
+
+func TestReaderReorderWindow(t *testing.T) {
+	const stream = "id: 1\ndata: a\n\nid: 3\ndata: c\n\nid: 2\ndata: b\n\nid: 6\ndata: f\n\nid: 5\ndata: e\n\n"
+
+	reader := NewReaderFromReader(strings.NewReader(stream), ReaderConfig{ReorderWindow: 3})
+	var ids []string
+	for reader.Next() {
+		ids = append(ids, reader.LastID())
+	}
+	if reader.Error() != nil {
+		t.Fatal(reader.Error())
+	}
+	if strings.Join(ids, ",") != "1,2,3,5,6" {
+		t.Fatalf("unexpected order %v", ids)
+	}
+}
+
+func TestReaderReorderWindowLateMessage(t *testing.T) {
+	pr, pw := io.Pipe()
+	release := make(chan struct{})
+	go func() {
+		for _, id := range []string{"1", "2", "3", "4", "5", "2", "6"} {
+			_, _ = io.WriteString(pw, "id: "+id+"\ndata: x\n\n")
+		}
+		<-release
+		_, _ = io.WriteString(pw, "id: 8\ndata: x\n\n")
+		_ = pw.Close()
+	}()
+
+	reader := NewReaderFromReader(pr, ReaderConfig{ReorderWindow: 3})
+	delivered := make(chan string)
+	go func() {
+		for reader.Next() {
+			delivered <- reader.LastID()
+		}
+		close(delivered)
+	}()
+
+	var ids []string
+	for len(ids) < 7 {
+		select {
+		case id := <-delivered:
+			ids = append(ids, id)
+		case <-time.After(time.Second):
+			t.Fatalf("6 was held back, delivered %v", ids)
+		}
+	}
+	close(release)
+	for id := range delivered {
+		ids = append(ids, id)
+	}
+	if strings.Join(ids, ",") != "1,2,3,4,5,2,6,8" {
+		t.Fatalf("unexpected order %v", ids)
+	}
+}
+
+func TestReaderDeliverEmptyComments(t *testing.T) {
+	reader := NewReaderFromReader(strings.NewReader(":\n\n:\n: b\n\n"), ReaderConfig{DeliverComments: true})
+
+	var comments []string
+	for reader.Next() {
+		current, _ := reader.Current()
+		if current.Comment == nil {
+			t.Fatal("bare comment line was lost")
+		}
+		comments = append(comments, string(current.Comment))
+	}
+	if len(comments) != 2 || comments[0] != "" || comments[1] != "\nb" {
+		t.Fatalf("unexpected comments %q", comments)
+	}
+
+	marshal, _ := (&Message{Comment: []byte{}}).Marshal()
+	if string(marshal) != ":\n\n" {
+		t.Fatalf("unexpected encoding %q", marshal)
+	}
+}
+
+func TestReaderReorderWindowIdleStream(t *testing.T) {
+	pr, pw := io.Pipe()
+	defer pw.Close()
+	go func() {
+		_, _ = io.WriteString(pw, "id: 1\ndata: a\n\nid: 3\ndata: c\n\n")
+	}()
+
+	reader := NewReaderFromReader(pr, ReaderConfig{
+		ReorderWindow:  3,
+		ReorderTimeout: 50 * time.Millisecond,
+	})
+	defer reader.Close()
+
+	delivered := make(chan string)
+	go func() {
+		for reader.Next() {
+			delivered <- reader.LastID()
+		}
+	}()
+
+	for _, want := range []string{"1", "3"} {
+		select {
+		case id := <-delivered:
+			if id != want {
+				t.Fatalf("got id %s, want %s", id, want)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("id %s was not delivered on an idle stream", want)
+		}
+	}
+}
+
+func TestReaderReorderWindowFirstMessage(t *testing.T) {
+	pr, pw := io.Pipe()
+	defer pw.Close()
+	go func() {
+		_, _ = io.WriteString(pw, "id: 7\ndata: a\n\n")
+	}()
+
+	reader := NewReaderFromReader(pr, ReaderConfig{ReorderWindow: 3})
+	defer reader.Close()
+
+	delivered := make(chan string)
+	go func() {
+		for reader.Next() {
+			delivered <- reader.LastID()
+		}
+	}()
+
+	select {
+	case id := <-delivered:
+		if id != "7" {
+			t.Fatalf("got id %s, want 7", id)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("first message was held back on an idle stream")
+	}
+}
@@ -0,0 +1,88 @@
+package sse
+
+import (
+	"slices"
+	"strconv"
+	"time"
+)
+
+type reorderEntry struct {
+	id       int64
+	message  Message
+	received time.Time
+}
+
+// reorderBuffer holds up to window messages and releases them ordered by numeric ID.
+// The first message is released right away and sets the expected ID. After that a
+// message is released as soon as it is the next expected ID, otherwise once the
+// window is full or a buffered message has waited for timeout, in which case the
+// gap is skipped. A message whose ID is below the next expected one arrived too
+// late to be ordered and is released right away.
+type reorderBuffer struct {
+	window  int
+	timeout time.Duration
+	entries []reorderEntry
+	next    int64
+	started bool
+}
+
+func newReorderBuffer(window int, timeout time.Duration) *reorderBuffer {
+	return &reorderBuffer{
+		window:  window,
+		timeout: timeout,
+		entries: make([]reorderEntry, 0, window),
+	}
+}
+
+// push adds the message to the buffer, it returns false when the message ID
+// is not numeric and therefore cannot be ordered.
+func (b *reorderBuffer) push(message Message, received time.Time) bool {
+	id, err := strconv.ParseInt(message.ID, 10, 64)
+	if err != nil {
+		return false
+	}
+	i, _ := slices.BinarySearchFunc(b.entries, id, func(e reorderEntry, id int64) int {
+		switch {
+		case e.id < id:
+			return -1
+		case e.id > id:
+			return 1
+		}
+		return 0
+	})
+	b.entries = slices.Insert(b.entries, i, reorderEntry{id: id, message: message, received: received})
+	return true
+}
+
+// pop releases the message with the lowest ID if it is due, or unconditionally when drain is set.
+func (b *reorderBuffer) pop(drain bool) (Message, bool) {
+	if len(b.entries) == 0 {
+		return Message{}, false
+	}
+	first := b.entries[0]
+	if !drain &&
+		b.started &&
+		len(b.entries) < b.window &&
+		first.id > b.next {
+		return Message{}, false
+	}
+	b.entries = b.entries[1:]
+	b.started = true
+	b.next = max(b.next, first.id+1)
+	return first.message, true
+}
+
+// deadline returns when the longest waiting buffered message times out,
+// it returns false when the buffer is empty or no timeout is set.
+func (b *reorderBuffer) deadline() (time.Time, bool) {
+	if b.timeout <= 0 || len(b.entries) == 0 {
+		return time.Time{}, false
+	}
+	oldest := b.entries[0].received
+	for _, entry := range b.entries[1:] {
+		if entry.received.Before(oldest) {
+			oldest = entry.received
+		}
+	}
+	return oldest.Add(b.timeout), true
+}